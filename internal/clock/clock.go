@@ -0,0 +1,70 @@
+// Package clock centralizes jut's notion of "now" so that --at can make
+// expiry checks and timestamp rendering evaluate a token as of a specific
+// moment instead of the wall clock.
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var now = time.Now
+
+// Now returns the current time, or the time fixed by SetFixed if --at was
+// given.
+func Now() time.Time {
+	return now()
+}
+
+// SetFixed makes Now always return t.
+func SetFixed(t time.Time) {
+	now = func() time.Time { return t }
+}
+
+// ParseAt parses the value of --at. It accepts, in order:
+//
+//   - RFC3339, e.g. "2025-01-01T00:00:00Z"
+//   - a date, e.g. "2025-01-01" (midnight UTC)
+//   - a bare Unix timestamp in seconds, or "sec.nsec"
+//   - a Go duration offset relative to reference, e.g. "-5m" or "+1h"
+func ParseAt(s string, reference time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, ok := parseUnix(s); ok {
+		return t, nil
+	}
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		d, err := time.ParseDuration(s)
+		if err == nil {
+			return reference.Add(d), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q (want RFC3339, YYYY-MM-DD, unix timestamp, or a +/-duration)", s)
+}
+
+// parseUnix handles a bare Unix timestamp, either whole seconds or
+// "sec.nsec".
+func parseUnix(s string) (time.Time, bool) {
+	sec, nsec, ok := strings.Cut(s, ".")
+	secs, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if !ok {
+		return time.Unix(secs, 0), true
+	}
+	nsecs, err := strconv.ParseInt(nsec, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for i := len(nsec); i < 9; i++ {
+		nsecs *= 10
+	}
+	return time.Unix(secs, nsecs), true
+}
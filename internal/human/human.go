@@ -0,0 +1,88 @@
+// Package human formats durations the way a person reads them: "3 hours",
+// "2 weeks", "just now", with correct pluralization. Duration gives the
+// full-length form used in headline messages; DurationCompact keeps the
+// terse "1h 2m" style needed to keep tabular output aligned.
+package human
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	day   = 24 * time.Hour
+	week  = 7 * day
+	month = 30 * day
+	year  = 365 * day
+
+	daysPerMonth = 30.44
+	daysPerYear  = 365.25
+)
+
+// Duration renders d the way a person would say it out loud: "just now",
+// "3 minutes", "2 hours 15 minutes", "1 week", "3 months", "2 years".
+// Negative durations are treated as their magnitude, like time.Duration.Abs.
+func Duration(d time.Duration) string {
+	d = d.Abs()
+
+	switch {
+	case d < 5*time.Second:
+		return "just now"
+	case d < time.Minute:
+		return plural(int(d.Seconds()), "second")
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < day:
+		h := int(d.Hours())
+		m := int(d.Minutes()) % 60
+		if m == 0 {
+			return plural(h, "hour")
+		}
+		return plural(h, "hour") + " " + plural(m, "minute")
+	case d < week:
+		return plural(int(d.Hours()/24), "day")
+	case d < month:
+		return plural(int(d.Hours()/24/7), "week")
+	case d < year:
+		return plural(int(math.Round(d.Hours()/24/daysPerMonth)), "month")
+	default:
+		return plural(int(math.Round(d.Hours()/24/daysPerYear)), "year")
+	}
+}
+
+// DurationCompact renders d in the short "1h 2m" style jut has always used
+// in the tabular DATES block, where full words would break alignment.
+func DurationCompact(d time.Duration) string {
+	d = d.Abs()
+
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < day {
+		h := int(d.Hours())
+		m := int(d.Minutes()) % 60
+		if m > 0 {
+			return fmt.Sprintf("%dh %dm", h, m)
+		}
+		return fmt.Sprintf("%dh", h)
+	}
+	days := int(d.Hours() / 24)
+	if days < 30 {
+		return fmt.Sprintf("%dd", days)
+	}
+	if days < 365 {
+		return fmt.Sprintf("%dmo", days/30)
+	}
+	return fmt.Sprintf("%dy", days/365)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,56 @@
+package human
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "just now"},
+		{4 * time.Second, "just now"},
+		{5 * time.Second, "5 seconds"},
+		{6 * time.Second, "6 seconds"},
+		{59 * time.Second, "59 seconds"},
+		{60 * time.Second, "1 minute"},
+		{3599 * time.Second, "59 minutes"},
+		{3600 * time.Second, "1 hour"},
+		{3660 * time.Second, "1 hour 1 minute"},
+		{86399 * time.Second, "23 hours 59 minutes"},
+		{86400 * time.Second, "1 day"},
+		{7 * 24 * time.Hour, "1 week"},
+		{30 * 24 * time.Hour, "1 month"},
+		{365 * 24 * time.Hour, "1 year"},
+		{-5 * time.Minute, "5 minutes"},
+	}
+	for _, c := range cases {
+		if got := Duration(c.d); got != c.want {
+			t.Errorf("Duration(%v) = %q, want %q", c.d, got, c.want)
+		}
+	}
+}
+
+func TestDurationCompact(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{59 * time.Second, "59s"},
+		{60 * time.Second, "1m"},
+		{3600 * time.Second, "1h"},
+		{3660 * time.Second, "1h 1m"},
+		{86400 * time.Second, "1d"},
+		{30 * 24 * time.Hour, "1mo"},
+		{365 * 24 * time.Hour, "1y"},
+		{-90 * time.Second, "1m"},
+	}
+	for _, c := range cases {
+		if got := DurationCompact(c.d); got != c.want {
+			t.Errorf("DurationCompact(%v) = %q, want %q", c.d, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Package jwks fetches and parses JSON Web Key Sets, with a small on-disk
+// cache keyed by the source host and honoring the response's Cache-Control
+// max-age.
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JWK is a single entry of a JSON Web Key Set, covering the fields jut
+// needs to verify RSA, EC, OKP (Ed25519) and oct (HMAC) keys.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC / OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// oct (symmetric, rarely published but handled for completeness)
+	K string `json:"k,omitempty"`
+}
+
+// Set is a JSON Web Key Set as defined by RFC 7517.
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Find returns the key matching kid (when non-empty) and alg (when the key
+// declares one), the way implementations are expected to select a key per
+// RFC 7517 §5.
+func (s Set) Find(kid, alg string) (JWK, bool) {
+	for _, k := range s.Keys {
+		if kid != "" && k.Kid != kid {
+			continue
+		}
+		if k.Alg != "" && alg != "" && k.Alg != alg {
+			continue
+		}
+		return k, true
+	}
+	return JWK{}, false
+}
+
+// PublicKey converts the JWK into a crypto.PublicKey usable with the
+// standard library's Verify functions. HMAC ("oct") keys are returned as a
+// raw []byte instead, since they're symmetric.
+func (k JWK) PublicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid RSA modulus: %w", err)
+		}
+		eb, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid RSA exponent: %w", err)
+		}
+		e := new(big.Int).SetBytes(eb).Int64()
+		return &rsa.PublicKey{N: n, E: int(e)}, nil
+	case "EC":
+		curve, err := curveFor(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid EC x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid EC y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwks: unsupported OKP curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid Ed25519 x: %w", err)
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: invalid oct key: %w", err)
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported key type %q", k.Kty)
+	}
+}
+
+func curveFor(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported EC curve %q", crv)
+	}
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	if l := len(s) % 4; l > 0 {
+		s += strings.Repeat("=", 4-l)
+	}
+	return base64.URLEncoding.DecodeString(s)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := decodeSegment(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// cacheEntry wraps a fetched Set with the bookkeeping needed to honor
+// Cache-Control: max-age on the next run.
+type cacheEntry struct {
+	FetchedAt int64           `json:"fetched_at"`
+	MaxAge    int64           `json:"max_age"`
+	Set       json.RawMessage `json:"set"`
+}
+
+func (e cacheEntry) expired(now time.Time) bool {
+	if e.MaxAge <= 0 {
+		return true
+	}
+	return now.Unix() >= e.FetchedAt+e.MaxAge
+}
+
+// Load fetches the JWK Set from src, which may be a local file path or an
+// http(s) URL. Remote fetches are cached on disk under
+// ~/.cache/jut/jwks/<host>.json honoring the response's Cache-Control
+// max-age; a fresh cache entry is reused without hitting the network.
+func Load(src string) (Set, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return Set{}, fmt.Errorf("jwks: reading %s: %w", src, err)
+		}
+		return parse(b)
+	}
+	return loadRemote(src)
+}
+
+func loadRemote(rawURL string) (Set, error) {
+	cachePath, err := cachePathFor(rawURL)
+	if err == nil {
+		if entry, ok := readCache(cachePath); ok && !entry.expired(time.Now()) {
+			return parse(entry.Set)
+		}
+	}
+
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return Set{}, fmt.Errorf("jwks: fetching %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Set{}, fmt.Errorf("jwks: fetching %s: unexpected status %s", rawURL, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Set{}, fmt.Errorf("jwks: reading response from %s: %w", rawURL, err)
+	}
+
+	set, err := parse(body)
+	if err != nil {
+		return Set{}, err
+	}
+
+	if cachePath != "" {
+		entry := cacheEntry{
+			FetchedAt: time.Now().Unix(),
+			MaxAge:    maxAge(resp.Header.Get("Cache-Control")),
+			Set:       json.RawMessage(body),
+		}
+		writeCache(cachePath, entry)
+	}
+
+	return set, nil
+}
+
+func parse(b []byte) (Set, error) {
+	var set Set
+	if err := json.Unmarshal(b, &set); err != nil {
+		return Set{}, fmt.Errorf("jwks: invalid JWK Set: %w", err)
+	}
+	return set, nil
+}
+
+// maxAge parses a Cache-Control header value looking for a max-age
+// directive, the way an OIDC client caching jwks_uri responses is expected
+// to. Returns 0 if none is present or it's malformed.
+func maxAge(header string) int64 {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "max-age=") {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimPrefix(part, "max-age="), 10, 64)
+		if err != nil {
+			continue
+		}
+		return n
+	}
+	return 0
+}
+
+func cachePathFor(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "jut", "jwks", u.Hostname()+".json"), nil
+}
+
+func readCache(path string) (cacheEntry, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func writeCache(path string, entry cacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,147 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func b64(b []byte) string {
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b)
+}
+
+func TestJWKPublicKeyRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk := JWK{
+		Kty: "RSA",
+		N:   b64(priv.N.Bytes()),
+		E:   b64(big.NewInt(int64(priv.E)).Bytes()),
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("got %T, want *rsa.PublicKey", pub)
+	}
+	if !rsaPub.Equal(&priv.PublicKey) {
+		t.Errorf("round-tripped RSA key does not match original")
+	}
+}
+
+func TestJWKPublicKeyEC(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := (priv.Curve.Params().BitSize + 7) / 8
+	jwk := JWK{
+		Kty: "EC", Crv: "P-256",
+		X: b64(priv.X.FillBytes(make([]byte, size))),
+		Y: b64(priv.Y.FillBytes(make([]byte, size))),
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		t.Fatalf("got %T, want *ecdsa.PublicKey", pub)
+	}
+	if !ecPub.Equal(&priv.PublicKey) {
+		t.Errorf("round-tripped EC key does not match original")
+	}
+}
+
+func TestJWKPublicKeyOKP(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk := JWK{Kty: "OKP", Crv: "Ed25519", X: b64(pub)}
+	got, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, ok := got.(ed25519.PublicKey)
+	if !ok {
+		t.Fatalf("got %T, want ed25519.PublicKey", got)
+	}
+	if !edPub.Equal(pub) {
+		t.Errorf("round-tripped Ed25519 key does not match original")
+	}
+}
+
+func TestJWKPublicKeyHMAC(t *testing.T) {
+	secret := []byte("sssh")
+	jwk := JWK{Kty: "oct", K: b64(secret)}
+	got, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := got.([]byte)
+	if !ok || string(b) != string(secret) {
+		t.Errorf("PublicKey() = %v, want %q", got, secret)
+	}
+}
+
+func TestSetFind(t *testing.T) {
+	set := Set{Keys: []JWK{
+		{Kid: "a", Alg: "RS256"},
+		{Kid: "b", Alg: "ES256"},
+	}}
+
+	if _, ok := set.Find("a", "RS256"); !ok {
+		t.Errorf("expected to find kid=a")
+	}
+	if _, ok := set.Find("a", "ES256"); ok {
+		t.Errorf("expected alg mismatch to reject kid=a")
+	}
+	if _, ok := set.Find("missing", ""); ok {
+		t.Errorf("expected no match for unknown kid")
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	cases := []struct {
+		header string
+		want   int64
+	}{
+		{"max-age=3600", 3600},
+		{"public, max-age=60, must-revalidate", 60},
+		{"no-cache", 0},
+		{"", 0},
+		{"max-age=not-a-number", 0},
+	}
+	for _, c := range cases {
+		if got := maxAge(c.header); got != c.want {
+			t.Errorf("maxAge(%q) = %d, want %d", c.header, got, c.want)
+		}
+	}
+}
+
+func TestCacheEntryExpired(t *testing.T) {
+	now := time.Now()
+	fresh := cacheEntry{FetchedAt: now.Unix(), MaxAge: 3600}
+	if fresh.expired(now.Add(30 * time.Minute)) {
+		t.Errorf("expected entry within max-age to be fresh")
+	}
+	if !fresh.expired(now.Add(2 * time.Hour)) {
+		t.Errorf("expected entry past max-age to be expired")
+	}
+
+	noMaxAge := cacheEntry{FetchedAt: now.Unix(), MaxAge: 0}
+	if !noMaxAge.expired(now) {
+		t.Errorf("expected a zero max-age entry to always be treated as expired")
+	}
+}
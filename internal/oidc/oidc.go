@@ -0,0 +1,50 @@
+// Package oidc resolves an OpenID Connect issuer's discovery document so
+// callers can find its JWKS endpoint without hard-coding it.
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Discovery is the subset of the OIDC discovery document
+// (`.well-known/openid-configuration`) that jut cares about.
+type Discovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// Discover fetches <issuer>/.well-known/openid-configuration and returns
+// its parsed contents.
+func Discover(issuer string) (Discovery, error) {
+	u := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	resp, err := http.Get(u)
+	if err != nil {
+		return Discovery{}, fmt.Errorf("oidc: fetching %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Discovery{}, fmt.Errorf("oidc: fetching %s: unexpected status %s", u, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Discovery{}, fmt.Errorf("oidc: reading discovery document from %s: %w", u, err)
+	}
+
+	var doc Discovery
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return Discovery{}, fmt.Errorf("oidc: invalid discovery document from %s: %w", u, err)
+	}
+	if doc.JWKSURI == "" {
+		return Discovery{}, fmt.Errorf("oidc: discovery document from %s has no jwks_uri", u)
+	}
+	if doc.Issuer != "" && doc.Issuer != issuer {
+		return Discovery{}, fmt.Errorf("oidc: discovery document from %s declares issuer %q, want %q", u, doc.Issuer, issuer)
+	}
+	return doc, nil
+}
@@ -0,0 +1,59 @@
+package oidc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newDiscoveryServer starts a server whose discovery document advertises
+// issuer (the handler is registered after the server starts, since the
+// issuer is the server's own URL).
+func newDiscoveryServer(t *testing.T, issuer func(serverURL string) string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer": %q, "jwks_uri": %q}`, issuer(srv.URL), srv.URL+"/jwks.json")
+	})
+	return srv
+}
+
+func TestDiscoverMatchingIssuer(t *testing.T) {
+	srv := newDiscoveryServer(t, func(serverURL string) string { return serverURL })
+
+	doc, err := Discover(srv.URL)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if doc.JWKSURI != srv.URL+"/jwks.json" {
+		t.Errorf("JWKSURI = %q, want %q", doc.JWKSURI, srv.URL+"/jwks.json")
+	}
+}
+
+func TestDiscoverMismatchedIssuerRejected(t *testing.T) {
+	srv := newDiscoveryServer(t, func(string) string { return "https://spoofed.example" })
+
+	if _, err := Discover(srv.URL); err == nil {
+		t.Fatal("expected an error when the discovery document's issuer doesn't match the requested one")
+	}
+}
+
+func TestDiscoverIssuerlessDocAccepted(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jwks_uri": %q}`, srv.URL+"/jwks.json")
+	})
+
+	doc, err := Discover(srv.URL)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if doc.JWKSURI != srv.URL+"/jwks.json" {
+		t.Errorf("JWKSURI = %q, want %q", doc.JWKSURI, srv.URL+"/jwks.json")
+	}
+}
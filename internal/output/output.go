@@ -0,0 +1,262 @@
+// Package output renders a decoded JWT in one of several formats selected
+// by the -o/--output flag: text (the default colored view), json,
+// json-compact, yaml, or a user-supplied text/template.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tidwall/pretty"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jack-work/jut/internal/claims"
+	"github.com/jack-work/jut/internal/human"
+	"github.com/jack-work/jut/internal/verify"
+)
+
+// Timestamp is a single registered-claim timestamp (iat/nbf/exp) rendered
+// for the DATES block and exposed to templates.
+type Timestamp struct {
+	Name      string
+	Formatted string
+	Relative  string
+}
+
+// Data is everything a Format needs to render a decoded token, independent
+// of how it was verified or decoded.
+type Data struct {
+	Header  map[string]interface{}
+	Payload map[string]interface{}
+
+	Timestamps []Timestamp
+
+	HasExpiry bool
+	Expired   bool
+	ExpiresIn time.Duration // negative once the token has expired
+
+	// EvaluatedAt is the reference time expiry was checked against: now,
+	// or the time given by --at.
+	EvaluatedAt time.Time
+
+	// Claims is the semantic rendering of registered claims for the
+	// CLAIMS block; empty (and the block skipped) when --raw is set.
+	Claims []claims.Rendered
+
+	Signature *verify.Result
+}
+
+// Format renders Data to w.
+type Format interface {
+	Render(w io.Writer, d Data) error
+}
+
+// New resolves the value of -o/--output into a Format. "template=..." is
+// parsed as a Go text/template; everything else must name a known format.
+func New(spec string) (Format, error) {
+	if tmpl, ok := strings.CutPrefix(spec, "template="); ok {
+		t, err := template.New("jut").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		return templateFormat{t}, nil
+	}
+
+	switch spec {
+	case "", "text":
+		return textFormat{}, nil
+	case "json":
+		return jsonFormat{indent: true}, nil
+	case "json-compact":
+		return jsonFormat{indent: false}, nil
+	case "yaml":
+		return yamlFormat{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want text, json, json-compact, yaml, or template=...)", spec)
+	}
+}
+
+// document is the shape shared by the json and yaml formats.
+func (d Data) document() map[string]interface{} {
+	doc := map[string]interface{}{
+		"header":       normalizeNumbers(d.Header),
+		"payload":      normalizeNumbers(d.Payload),
+		"evaluated_at": d.EvaluatedAt.UTC().Format(time.RFC3339),
+	}
+	if d.Signature != nil {
+		doc["signature"] = map[string]interface{}{
+			"verified": d.Signature.Verified,
+			"alg":      d.Signature.Alg,
+			"kid":      d.Signature.Kid,
+		}
+	}
+	return doc
+}
+
+// normalizeNumbers converts whole-number float64 values (the numeric type
+// encoding/json always decodes into) back to int64, so the yaml encoder
+// doesn't render claims like exp/iat in scientific notation.
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = normalizeNumbers(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = normalizeNumbers(val)
+		}
+		return out
+	case float64:
+		if i := int64(v); float64(i) == v {
+			return i
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+type jsonFormat struct{ indent bool }
+
+func (f jsonFormat) Render(w io.Writer, d Data) error {
+	var b []byte
+	var err error
+	if f.indent {
+		b, err = json.MarshalIndent(d.document(), "", "  ")
+	} else {
+		b, err = json.Marshal(d.document())
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+type yamlFormat struct{}
+
+func (yamlFormat) Render(w io.Writer, d Data) error {
+	return yaml.NewEncoder(w).Encode(d.document())
+}
+
+// templateData is the view a user-supplied template sees; it drops
+// exported-but-irrelevant internals of Data and surfaces the fields
+// documented for -o template=.
+type templateData struct {
+	Header      map[string]interface{}
+	Payload     map[string]interface{}
+	Expired     bool
+	ExpiresIn   time.Duration
+	Timestamps  map[string]string
+	EvaluatedAt time.Time
+}
+
+type templateFormat struct{ t *template.Template }
+
+func (f templateFormat) Render(w io.Writer, d Data) error {
+	ts := make(map[string]string, len(d.Timestamps))
+	for _, t := range d.Timestamps {
+		ts[t.Name] = t.Formatted
+	}
+	err := f.t.Execute(w, templateData{
+		Header:      d.Header,
+		Payload:     d.Payload,
+		Expired:     d.Expired,
+		ExpiresIn:   d.ExpiresIn,
+		Timestamps:  ts,
+		EvaluatedAt: d.EvaluatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// textFormat is the original colored, human-oriented view.
+type textFormat struct{}
+
+const (
+	dim   = "\033[2m"
+	bold  = "\033[1m"
+	reset = "\033[0m"
+	cyan  = "\033[36m"
+	green = "\033[32m"
+	red   = "\033[31m"
+)
+
+func (textFormat) Render(w io.Writer, d Data) error {
+	header, err := json.Marshal(d.Header)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(d.Payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s%s── HEADER ──%s\n", bold, cyan, reset)
+	fmt.Fprintln(w, string(pretty.Color(pretty.Pretty(header), nil)))
+
+	fmt.Fprintf(w, "%s%s── PAYLOAD ─%s\n", bold, green, reset)
+	fmt.Fprintln(w, string(pretty.Color(pretty.Pretty(payload), nil)))
+
+	if len(d.Claims) > 0 {
+		fmt.Fprintf(w, "%s%s── CLAIMS ──%s\n", bold, cyan, reset)
+		for _, c := range d.Claims {
+			for _, line := range c.Lines {
+				fmt.Fprintf(w, "  %s%s%s\n", dim, line, reset)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(d.Timestamps) > 0 {
+		fmt.Fprintf(w, "%s%s── DATES ───%s\n", bold, dim, reset)
+		for _, ts := range d.Timestamps {
+			fmt.Fprintf(w, "  %s%-4s%s %s", dim, ts.Name+":", reset, ts.Formatted)
+			if ts.Relative != "" {
+				fmt.Fprintf(w, "  %s(%s)%s", dim, ts.Relative, reset)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if d.HasExpiry {
+		if d.Expired {
+			ago := human.Duration(d.ExpiresIn)
+			fmt.Fprintf(w, "  %s%s✗ EXPIRED%s %s(%s ago)%s\n\n", bold, red, reset, dim, ago, reset)
+		} else {
+			remaining := human.Duration(d.ExpiresIn)
+			fmt.Fprintf(w, "  %s%s✓ VALID%s %s(expires in %s)%s\n\n", bold, green, reset, dim, remaining, reset)
+		}
+	}
+
+	if d.Signature != nil {
+		fmt.Fprintf(w, "%s%s── SIGNATURE ──%s\n", bold, cyan, reset)
+		fmt.Fprintf(w, "  alg: %s  kid: %s\n", d.Signature.Alg, d.Signature.Kid)
+		for _, c := range d.Signature.Checks {
+			mark, color := "✓", green
+			if !c.Pass {
+				mark, color = "✗", red
+			}
+			detail := ""
+			if c.Detail != "" {
+				detail = fmt.Sprintf("  %s(%s)%s", dim, c.Detail, reset)
+			}
+			fmt.Fprintf(w, "  %s%s %s%-9s%s%s\n", color, mark, reset, c.Name+":", detail, reset)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
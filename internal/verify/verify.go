@@ -0,0 +1,250 @@
+// Package verify checks a JWT's signature against a JWK Set and validates
+// its standard time- and identity-related claims.
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jack-work/jut/internal/jwks"
+)
+
+// Check is the pass/fail outcome of a single validation, e.g. the signature
+// itself or a claim like iss/aud/exp.
+type Check struct {
+	Name   string
+	Pass   bool
+	Detail string
+}
+
+// Result is the outcome of verifying a token: whether its signature is
+// valid, which key was used, and the result of each claim check performed
+// alongside it.
+type Result struct {
+	Verified bool
+	Alg      string
+	Kid      string
+	Checks   []Check
+	Err      error
+}
+
+// Options configures how a token is verified.
+type Options struct {
+	Issuer   string // --issuer; checked against the iss claim
+	Audience string // --aud; checked against the aud claim
+	Secret   string // --secret, for HS256/384/512
+}
+
+// Verify checks signingInput's signature against set using the algorithm
+// and key id declared in header, then validates iss/aud/nbf/exp in payload
+// against now. Result.Verified is the overall pass/fail across the
+// signature and every claim check, so callers can gate on it directly
+// (e.g. a CI script doing `jut --verify $TOK && deploy` must not proceed
+// on a token with a valid signature but an expired exp or wrong iss).
+func Verify(signingInput string, sig []byte, header, payload map[string]interface{}, set jwks.Set, opts Options, now time.Time) Result {
+	alg, _ := header["alg"].(string)
+	kid, _ := header["kid"].(string)
+	result := Result{Alg: alg, Kid: kid}
+
+	pass, detail, err := verifySignature(signingInput, sig, alg, kid, set, opts.Secret)
+	if err != nil {
+		result.Err = err
+		result.Checks = append(result.Checks, Check{Name: "signature", Pass: false, Detail: err.Error()})
+		return result
+	}
+	result.Checks = append(result.Checks, Check{Name: "signature", Pass: pass, Detail: detail})
+
+	result.Checks = append(result.Checks, checkIssuer(payload, opts.Issuer))
+	result.Checks = append(result.Checks, checkAudience(payload, opts.Audience))
+	result.Checks = append(result.Checks, checkNotBefore(payload, now))
+	result.Checks = append(result.Checks, checkExpiry(payload, now))
+
+	result.Verified = true
+	for _, c := range result.Checks {
+		result.Verified = result.Verified && c.Pass
+	}
+
+	return result
+}
+
+func verifySignature(signingInput string, sig []byte, alg, kid string, set jwks.Set, secret string) (bool, string, error) {
+	if strings.HasPrefix(alg, "HS") {
+		if secret == "" {
+			return false, "", fmt.Errorf("%s requires --secret", alg)
+		}
+		return verifyHMAC(signingInput, sig, alg, []byte(secret))
+	}
+
+	key, ok := set.Find(kid, alg)
+	if !ok {
+		return false, "", fmt.Errorf("no matching key for kid %q alg %q in JWKS", kid, alg)
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		return false, "", err
+	}
+
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		rsaKey, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return false, "", fmt.Errorf("key %q is not an RSA key", kid)
+		}
+		h, hashed, err := hashInput(alg, signingInput)
+		if err != nil {
+			return false, "", err
+		}
+		pass := rsa.VerifyPKCS1v15(rsaKey, h, hashed, sig) == nil
+		return pass, fmt.Sprintf("verified with key %q", kid), nil
+	case strings.HasPrefix(alg, "PS"):
+		rsaKey, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return false, "", fmt.Errorf("key %q is not an RSA key", kid)
+		}
+		h, hashed, err := hashInput(alg, signingInput)
+		if err != nil {
+			return false, "", err
+		}
+		pass := rsa.VerifyPSS(rsaKey, h, hashed, sig, nil) == nil
+		return pass, fmt.Sprintf("verified with key %q", kid), nil
+	case strings.HasPrefix(alg, "ES"):
+		ecKey, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return false, "", fmt.Errorf("key %q is not an EC key", kid)
+		}
+		_, hashed, err := hashInput(alg, signingInput)
+		if err != nil {
+			return false, "", err
+		}
+		ok, err = verifyECDSA(ecKey, hashed, sig)
+		return ok, fmt.Sprintf("verified with key %q", kid), err
+	case alg == "EdDSA":
+		edKey, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return false, "", fmt.Errorf("key %q is not an Ed25519 key", kid)
+		}
+		return ed25519.Verify(edKey, []byte(signingInput), sig), fmt.Sprintf("verified with key %q", kid), nil
+	default:
+		return false, "", fmt.Errorf("unsupported alg %q", alg)
+	}
+}
+
+func verifyHMAC(signingInput string, sig []byte, alg string, secret []byte) (bool, string, error) {
+	var h func() crypto.Hash
+	switch alg {
+	case "HS256":
+		h = func() crypto.Hash { return crypto.SHA256 }
+	case "HS384":
+		h = func() crypto.Hash { return crypto.SHA384 }
+	case "HS512":
+		h = func() crypto.Hash { return crypto.SHA512 }
+	default:
+		return false, "", fmt.Errorf("unsupported alg %q", alg)
+	}
+	mac := hmac.New(h().New, secret)
+	mac.Write([]byte(signingInput))
+	expected := mac.Sum(nil)
+	return subtle.ConstantTimeCompare(expected, sig) == 1, "verified with --secret", nil
+}
+
+func hashInput(alg, signingInput string) (crypto.Hash, []byte, error) {
+	switch alg[len(alg)-3:] {
+	case "256":
+		sum := sha256.Sum256([]byte(signingInput))
+		return crypto.SHA256, sum[:], nil
+	case "384":
+		sum := sha512.Sum384([]byte(signingInput))
+		return crypto.SHA384, sum[:], nil
+	case "512":
+		sum := sha512.Sum512([]byte(signingInput))
+		return crypto.SHA512, sum[:], nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported alg %q", alg)
+	}
+}
+
+// verifyECDSA checks an ES256/384/512 signature, which JWS encodes as the
+// raw concatenation R||S rather than ASN.1 DER.
+func verifyECDSA(pub *ecdsa.PublicKey, hashed, sig []byte) (bool, error) {
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	if len(sig) != 2*size {
+		return false, fmt.Errorf("malformed EC signature: got %d bytes, want %d", len(sig), 2*size)
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	return ecdsa.Verify(pub, hashed, r, s), nil
+}
+
+func checkIssuer(payload map[string]interface{}, wantIssuer string) Check {
+	iss, _ := payload["iss"].(string)
+	if wantIssuer == "" {
+		return Check{Name: "iss", Pass: iss != "", Detail: iss}
+	}
+	return Check{Name: "iss", Pass: iss == wantIssuer, Detail: iss}
+}
+
+func checkAudience(payload map[string]interface{}, wantAudience string) Check {
+	switch aud := payload["aud"].(type) {
+	case string:
+		if wantAudience == "" {
+			return Check{Name: "aud", Pass: aud != "", Detail: aud}
+		}
+		return Check{Name: "aud", Pass: aud == wantAudience, Detail: aud}
+	case []interface{}:
+		var parts []string
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		detail := strings.Join(parts, ", ")
+		if wantAudience == "" {
+			return Check{Name: "aud", Pass: len(parts) > 0, Detail: detail}
+		}
+		for _, p := range parts {
+			if p == wantAudience {
+				return Check{Name: "aud", Pass: true, Detail: detail}
+			}
+		}
+		return Check{Name: "aud", Pass: false, Detail: detail}
+	default:
+		return Check{Name: "aud", Pass: false, Detail: "missing"}
+	}
+}
+
+func checkNotBefore(payload map[string]interface{}, now time.Time) Check {
+	nbf, ok := payload["nbf"].(float64)
+	if !ok {
+		return Check{Name: "nbf", Pass: true, Detail: "not present"}
+	}
+	t := time.Unix(int64(nbf), 0)
+	return Check{Name: "nbf", Pass: !now.Before(t), Detail: t.Format("2006-01-02 15:04:05 MST")}
+}
+
+func checkExpiry(payload map[string]interface{}, now time.Time) Check {
+	exp, ok := payload["exp"].(float64)
+	if !ok {
+		return Check{Name: "exp", Pass: true, Detail: "not present"}
+	}
+	t := time.Unix(int64(exp), 0)
+	return Check{Name: "exp", Pass: now.Before(t), Detail: t.Format("2006-01-02 15:04:05 MST")}
+}
+
+// DecodeSignature base64url-decodes the third JWT segment.
+func DecodeSignature(seg string) ([]byte, error) {
+	if l := len(seg) % 4; l > 0 {
+		seg += strings.Repeat("=", 4-l)
+	}
+	return base64.URLEncoding.DecodeString(seg)
+}
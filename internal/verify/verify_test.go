@@ -0,0 +1,216 @@
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jack-work/jut/internal/jwks"
+)
+
+func b64(b []byte) string {
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b)
+}
+
+const signingInput = "header.payload"
+
+// keySet builds a one-key jwks.Set for kid from a public key, the way a
+// real JWKS endpoint would shape RSA/EC/OKP entries.
+func keySet(t *testing.T, kid string, pub interface{}) jwks.Set {
+	t.Helper()
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return jwks.Set{Keys: []jwks.JWK{{
+			Kty: "RSA", Kid: kid,
+			N: b64(k.N.Bytes()),
+			E: b64(big.NewInt(int64(k.E)).Bytes()),
+		}}}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		crv := map[elliptic.Curve]string{elliptic.P256(): "P-256", elliptic.P384(): "P-384", elliptic.P521(): "P-521"}[k.Curve]
+		return jwks.Set{Keys: []jwks.JWK{{
+			Kty: "EC", Kid: kid, Crv: crv,
+			X: b64(k.X.FillBytes(make([]byte, size))),
+			Y: b64(k.Y.FillBytes(make([]byte, size))),
+		}}}
+	case ed25519.PublicKey:
+		return jwks.Set{Keys: []jwks.JWK{{Kty: "OKP", Kid: kid, Crv: "Ed25519", X: b64(k)}}}
+	default:
+		t.Fatalf("keySet: unsupported key type %T", pub)
+		return jwks.Set{}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("sssh")
+
+	sum := sha256.Sum256([]byte(signingInput))
+
+	cases := []struct {
+		name string
+		alg  string
+		set  jwks.Set
+		sig  []byte
+	}{
+		{"HS256", "HS256", jwks.Set{}, hmacSign(secret)},
+		{"RS256", "RS256", keySet(t, "rsa-1", &rsaKey.PublicKey), mustSign(t, func() ([]byte, error) {
+			return rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, sum[:])
+		})},
+		{"PS256", "PS256", keySet(t, "rsa-1", &rsaKey.PublicKey), mustSign(t, func() ([]byte, error) {
+			return rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, sum[:], nil)
+		})},
+		{"ES256", "ES256", keySet(t, "ec-1", &ecKey.PublicKey), signES256(t, ecKey, sum[:])},
+		{"EdDSA", "EdDSA", keySet(t, "ed-1", edPub), ed25519.Sign(edPriv, []byte(signingInput))},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kid := ""
+			if len(c.set.Keys) > 0 {
+				kid = c.set.Keys[0].Kid
+			}
+			pass, _, err := verifySignature(signingInput, c.sig, c.alg, kid, c.set, string(secret))
+			if err != nil {
+				t.Fatalf("verifySignature: %v", err)
+			}
+			if !pass {
+				t.Fatalf("expected signature to verify")
+			}
+
+			tampered := append([]byte(nil), c.sig...)
+			tampered[0] ^= 0xFF
+			pass, _, err = verifySignature(signingInput, tampered, c.alg, kid, c.set, string(secret))
+			if err == nil && pass {
+				t.Fatalf("expected tampered signature to fail")
+			}
+		})
+	}
+}
+
+func TestVerifyECDSAMalformedLength(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = verifyECDSA(&ecKey.PublicKey, []byte("hashed"), []byte("too-short"))
+	if err == nil {
+		t.Fatalf("expected error for malformed EC signature length")
+	}
+}
+
+func TestVerifyOverallResult(t *testing.T) {
+	secret := []byte("sssh")
+	payload := map[string]interface{}{
+		"iss": "https://good.example",
+		"aud": "svc-a",
+		"exp": float64(time.Now().Add(time.Hour).Unix()),
+	}
+	header := map[string]interface{}{"alg": "HS256"}
+	sig := hmacSign(secret)
+
+	good := Verify(signingInput, sig, header, payload, jwks.Set{}, Options{
+		Issuer: "https://good.example", Audience: "svc-a", Secret: string(secret),
+	}, time.Now())
+	if !good.Verified {
+		t.Fatalf("expected Verified, got %+v", good)
+	}
+
+	// Valid signature, but aud doesn't match --aud: overall result must fail,
+	// even though the raw signature check passes.
+	badAud := Verify(signingInput, sig, header, payload, jwks.Set{}, Options{
+		Issuer: "https://good.example", Audience: "svc-b", Secret: string(secret),
+	}, time.Now())
+	if badAud.Verified {
+		t.Fatalf("expected Verified=false on audience mismatch, got %+v", badAud)
+	}
+
+	// Valid signature, expired token: overall result must fail too.
+	expired := map[string]interface{}{
+		"iss": "https://good.example",
+		"aud": "svc-a",
+		"exp": float64(time.Now().Add(-time.Hour).Unix()),
+	}
+	badExp := Verify(signingInput, sig, header, expired, jwks.Set{}, Options{
+		Issuer: "https://good.example", Audience: "svc-a", Secret: string(secret),
+	}, time.Now())
+	if badExp.Verified {
+		t.Fatalf("expected Verified=false on expired token, got %+v", badExp)
+	}
+}
+
+func TestCheckAudience(t *testing.T) {
+	cases := []struct {
+		name string
+		aud  interface{}
+		want string
+		pass bool
+	}{
+		{"string match, no --aud", "svc-a", "", true},
+		{"string match, matching --aud", "svc-a", "svc-a", true},
+		{"string mismatch", "svc-a", "svc-b", false},
+		{"array match", []interface{}{"svc-a", "svc-c"}, "svc-a", true},
+		{"array mismatch", []interface{}{"svc-a", "svc-c"}, "svc-b", false},
+		{"missing", nil, "svc-a", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			payload := map[string]interface{}{}
+			if c.aud != nil {
+				payload["aud"] = c.aud
+			}
+			got := checkAudience(payload, c.want)
+			if got.Pass != c.pass {
+				t.Errorf("checkAudience(%v, %q) = %+v, want Pass=%v", c.aud, c.want, got, c.pass)
+			}
+		})
+	}
+}
+
+func hmacSign(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func mustSign(t *testing.T, sign func() ([]byte, error)) []byte {
+	t.Helper()
+	sig, err := sign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, hashed []byte) []byte {
+	t.Helper()
+	r, s, err := ecdsa.Sign(rand.Reader, key, hashed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+	return sig
+}
@@ -1,31 +1,46 @@
 package main
 
 import (
-	"encoding/base64"
+	"bufio"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"math"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/atotto/clipboard"
-	"github.com/tidwall/pretty"
+	"github.com/jack-work/jut/internal/claims"
+	"github.com/jack-work/jut/internal/clock"
+	"github.com/jack-work/jut/internal/human"
+	"github.com/jack-work/jut/internal/jwks"
+	"github.com/jack-work/jut/internal/oidc"
+	"github.com/jack-work/jut/internal/output"
+	"github.com/jack-work/jut/internal/verify"
+	"github.com/jack-work/jut/pkg/jut"
 )
 
 var version = "dev"
 
 func main() {
-	jsonOut := flag.Bool("json", false, "output raw JSON (no colors, for piping)")
+	jsonOut := flag.Bool("json", false, "deprecated: use -o json")
+	outFormat := flag.String("o", "text", "output format: text, json, json-compact, yaml, template=<go-template>")
+	flag.StringVar(outFormat, "output", "text", "output format: text, json, json-compact, yaml, template=<go-template>")
 	showVersion := flag.Bool("version", false, "print version and exit")
+	doVerify := flag.Bool("verify", false, "verify the token's signature and standard claims")
+	jwksSource := flag.String("jwks", "", "JWK Set to verify against (URL or file path)")
+	issuer := flag.String("issuer", "", "OIDC issuer; discovers jwks_uri and checks iss")
+	audience := flag.String("aud", "", "expected audience; checked against the aud claim")
+	secret := flag.String("secret", "", "shared secret for HS256/384/512")
+	at := flag.String("at", "", "evaluate expiry as of this time instead of now (RFC3339, YYYY-MM-DD, unix timestamp, or +/-duration)")
+	raw := flag.Bool("raw", false, "suppress the semantic CLAIMS block")
+	batch := flag.Bool("batch", false, "treat stdin as one JWT per line (NDJSON out)")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "jut - JWT decoder for your terminal\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
 		fmt.Fprintf(os.Stderr, "  jut                  decode JWT from clipboard\n")
 		fmt.Fprintf(os.Stderr, "  jut <token>          decode a JWT\n")
-		fmt.Fprintf(os.Stderr, "  echo <token> | jut   read from stdin\n\n")
+		fmt.Fprintf(os.Stderr, "  echo <token> | jut   read from stdin\n")
+		fmt.Fprintf(os.Stderr, "  jut --batch          decode one JWT per line of stdin\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 	}
@@ -36,151 +51,204 @@ func main() {
 		os.Exit(0)
 	}
 
-	token := getToken(flag.Args())
-	parts := strings.Split(token, ".")
-	if len(parts) < 2 || len(parts) > 3 {
-		fatal("invalid JWT: expected 2 or 3 dot-separated segments, got %d", len(parts))
+	if *at != "" {
+		t, err := clock.ParseAt(*at, time.Now())
+		if err != nil {
+			fatal("invalid --at: %v", err)
+		}
+		clock.SetFixed(t)
 	}
 
-	header, err := decodeSegment(parts[0])
-	if err != nil {
-		fatal("failed to decode header: %v", err)
+	if *jsonOut {
+		fmt.Fprintln(os.Stderr, "jut: --json is deprecated, use -o json")
+		*outFormat = "json"
 	}
 
-	payload, err := decodeSegment(parts[1])
-	if err != nil {
-		fatal("failed to decode payload: %v", err)
+	if *batch {
+		runBatch(*outFormat, *raw, *doVerify, *jwksSource, *issuer, *audience, *secret)
+		return
 	}
 
-	if *jsonOut {
-		printJSON(header, payload)
-	} else {
-		printPretty(header, payload)
+	token, err := jut.GetToken(flag.Args())
+	if err != nil {
+		fatal("%v", err)
 	}
-}
-
-func getToken(args []string) string {
-	if len(args) > 0 {
-		return strings.TrimSpace(args[0])
+	tok, err := jut.Decode(token)
+	if err != nil {
+		fatal("%v", err)
 	}
 
-	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
-		b, err := io.ReadAll(os.Stdin)
+	var sigResult *verify.Result
+	if *doVerify {
+		parts := strings.Split(token, ".")
+		if len(parts) < 3 {
+			fatal("--verify requires a signed JWT (got %d segments)", len(parts))
+		}
+		alg, _ := tok.Header["alg"].(string)
+		set, err := resolveSet(*jwksSource, *issuer, alg)
 		if err != nil {
-			fatal("failed to read stdin: %v", err)
+			fatal("%v", err)
 		}
-		return strings.TrimSpace(string(b))
+		r, err := runVerify(parts, tok, set, *issuer, *audience, *secret)
+		if err != nil {
+			fatal("%v", err)
+		}
+		sigResult = &r
 	}
 
-	// No args, no pipe — try clipboard
-	text, err := clipboard.ReadAll()
+	format, err := output.New(*outFormat)
 	if err != nil {
-		fatal("failed to read clipboard: %v", err)
+		fatal("%v", err)
 	}
-	text = strings.TrimSpace(text)
-	if text == "" {
-		fatal("clipboard is empty")
-	}
-	return text
-}
 
-func decodeSegment(seg string) ([]byte, error) {
-	// JWT uses base64url encoding without padding
-	if l := len(seg) % 4; l > 0 {
-		seg += strings.Repeat("=", 4-l)
-	}
-	decoded, err := base64.URLEncoding.DecodeString(seg)
-	if err != nil {
-		return nil, err
+	if err := format.Render(os.Stdout, buildData(tok, sigResult, *raw)); err != nil {
+		fatal("failed to render output: %v", err)
 	}
 
-	// Re-marshal to get consistently formatted JSON
-	var obj map[string]interface{}
-	if err := json.Unmarshal(decoded, &obj); err != nil {
-		return nil, fmt.Errorf("invalid JSON: %v", err)
+	if sigResult != nil && !sigResult.Verified {
+		os.Exit(1)
 	}
-	return json.Marshal(obj)
 }
 
-func printJSON(header, payload []byte) {
-	out := map[string]json.RawMessage{
-		"header":  header,
-		"payload": payload,
-	}
-	b, _ := json.MarshalIndent(out, "", "  ")
-	fmt.Println(string(b))
+// batchRecord is one line of --batch's default NDJSON output.
+type batchRecord struct {
+	Token     string                 `json:"token"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	Expired   bool                   `json:"expired"`
+	Signature *batchSignature        `json:"signature,omitempty"`
+	Error     string                 `json:"error,omitempty"`
 }
 
-func printPretty(header, payload []byte) {
-	// Colors
-	dim := "\033[2m"
-	bold := "\033[1m"
-	reset := "\033[0m"
-	cyan := "\033[36m"
-	green := "\033[32m"
+// batchSignature is the --verify result attached to a batchRecord, the
+// same shape as the "signature" field in -o json.
+type batchSignature struct {
+	Verified bool   `json:"verified"`
+	Alg      string `json:"alg"`
+	Kid      string `json:"kid"`
+}
+
+// runBatch decodes one JWT per line of stdin (blank lines and #-comments
+// ignored), never aborting on a malformed token. With the default text
+// output format (whether implied or given as -o text) it emits one NDJSON
+// batchRecord per line; with any other -o (notably -o template=...) it
+// renders each token through that format instead, so log lines can be
+// piped straight into a template.
+func runBatch(outFormat string, raw, doVerify bool, jwksSource, issuer, audience, secret string) {
+	var format output.Format
+	if outFormat != "" && outFormat != "text" {
+		f, err := output.New(outFormat)
+		if err != nil {
+			fatal("%v", err)
+		}
+		format = f
+	}
 
-	fmt.Printf("\n%s%s── HEADER ──%s\n", bold, cyan, reset)
-	fmt.Println(string(pretty.Color(pretty.Pretty(header), nil)))
+	cache := newBatchJWKSCache(jwksSource, issuer)
 
-	fmt.Printf("%s%s── PAYLOAD ─%s\n", bold, green, reset)
-	fmt.Println(string(pretty.Color(pretty.Pretty(payload), nil)))
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
 
-	// Parse payload for timestamp info
-	var claims map[string]interface{}
-	if err := json.Unmarshal(payload, &claims); err != nil {
-		return
-	}
+		tok, err := jut.Decode(line)
+		if err != nil {
+			emitBatchError(format, line, err)
+			continue
+		}
 
-	timestamps := extractTimestamps(claims)
-	if len(timestamps) > 0 {
-		fmt.Printf("%s%s── DATES ───%s\n", bold, dim, reset)
-		for _, ts := range timestamps {
-			fmt.Printf("  %s%-4s%s %s%s%s", dim, ts.name+":", reset, "", ts.formatted, reset)
-			if ts.relative != "" {
-				fmt.Printf("  %s(%s)%s", dim, ts.relative, reset)
+		var sigResult *verify.Result
+		if doVerify {
+			parts := strings.Split(line, ".")
+			switch {
+			case len(parts) < 3:
+				emitBatchError(format, line, fmt.Errorf("--verify requires a signed JWT"))
+				continue
+			default:
+				alg, _ := tok.Header["alg"].(string)
+				set, err := cache.setFor(alg)
+				if err != nil {
+					emitBatchError(format, line, err)
+					continue
+				}
+				r, err := runVerify(parts, tok, set, issuer, audience, secret)
+				if err != nil {
+					emitBatchError(format, line, err)
+					continue
+				}
+				sigResult = &r
 			}
-			fmt.Println()
 		}
-		fmt.Println()
-	}
-
-	// Expiry check
-	if exp, ok := claims["exp"]; ok {
-		if expF, ok := exp.(float64); ok {
-			expTime := time.Unix(int64(expF), 0)
-			now := time.Now()
-			if now.After(expTime) {
-				red := "\033[31m"
-				ago := humanDuration(now.Sub(expTime))
-				fmt.Printf("  %s%s✗ EXPIRED%s %s(%s ago)%s\n\n", bold, red, reset, dim, ago, reset)
-			} else {
-				remaining := humanDuration(expTime.Sub(now))
-				fmt.Printf("  %s%s✓ VALID%s %s(expires in %s)%s\n\n", bold, green, reset, dim, remaining, reset)
+
+		if format == nil {
+			rec := batchRecord{
+				Token:   line,
+				Header:  tok.Header,
+				Payload: tok.Payload,
+				Expired: tok.Expired(clock.Now()),
+			}
+			if sigResult != nil {
+				rec.Signature = &batchSignature{
+					Verified: sigResult.Verified,
+					Alg:      sigResult.Alg,
+					Kid:      sigResult.Kid,
+				}
 			}
+			b, _ := json.Marshal(rec)
+			fmt.Println(string(b))
+			continue
+		}
+
+		if err := format.Render(os.Stdout, buildData(tok, sigResult, raw)); err != nil {
+			fatal("failed to render output: %v", err)
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		fatal("failed to read stdin: %v", err)
+	}
 }
 
-type tsInfo struct {
-	name      string
-	formatted string
-	relative  string
+func emitBatchError(format output.Format, token string, err error) {
+	if format != nil {
+		fmt.Fprintf(os.Stderr, "jut: skipping %q: %v\n", token, err)
+		return
+	}
+	b, _ := json.Marshal(batchRecord{Token: token, Error: err.Error()})
+	fmt.Println(string(b))
 }
 
-func extractTimestamps(claims map[string]interface{}) []tsInfo {
-	known := []struct {
-		key  string
-		name string
-	}{
-		{"iat", "iat"},
-		{"nbf", "nbf"},
-		{"exp", "exp"},
+// buildData assembles the output.Data for a decoded token: derived
+// timestamps, the semantic claims block (unless raw), and expiry,
+// evaluated against clock.Now() so --at applies consistently.
+func buildData(tok jut.Token, sig *verify.Result, raw bool) output.Data {
+	data := output.Data{
+		Header:      tok.Header,
+		Payload:     tok.Payload,
+		Timestamps:  buildTimestamps(tok.Payload),
+		Signature:   sig,
+		EvaluatedAt: clock.Now(),
+	}
+	if !raw {
+		data.Claims = claims.Render(tok.Payload)
 	}
+	if exp, ok := tok.Payload["exp"].(float64); ok {
+		expTime := time.Unix(int64(exp), 0)
+		now := clock.Now()
+		data.HasExpiry = true
+		data.Expired = now.After(expTime)
+		data.ExpiresIn = expTime.Sub(now)
+	}
+	return data
+}
 
-	var results []tsInfo
-	for _, k := range known {
-		val, ok := claims[k.key]
+func buildTimestamps(payload map[string]interface{}) []output.Timestamp {
+	known := []string{"iat", "nbf", "exp"}
+
+	var results []output.Timestamp
+	for _, key := range known {
+		val, ok := payload[key]
 		if !ok {
 			continue
 		}
@@ -189,45 +257,95 @@ func extractTimestamps(claims map[string]interface{}) []tsInfo {
 			continue
 		}
 		t := time.Unix(int64(f), 0)
-		rel := humanDuration(time.Since(t))
-		if time.Now().Before(t) {
+		rel := human.DurationCompact(clock.Now().Sub(t))
+		if clock.Now().Before(t) {
 			rel = "in " + rel
 		} else {
 			rel = rel + " ago"
 		}
-		results = append(results, tsInfo{
-			name:      k.name,
-			formatted: t.Format("2006-01-02 15:04:05 MST"),
-			relative:  rel,
+		results = append(results, output.Timestamp{
+			Name:      key,
+			Formatted: t.Format("2006-01-02 15:04:05 MST"),
+			Relative:  rel,
 		})
 	}
 	return results
 }
 
-func humanDuration(d time.Duration) string {
-	d = d.Abs()
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	}
-	if d < time.Hour {
-		return fmt.Sprintf("%dm", int(d.Minutes()))
+// resolveSet finds the JWK Set to verify against: directly from jwksSource,
+// or via OIDC discovery from issuer. HS* tokens need neither, since they're
+// checked against --secret instead.
+func resolveSet(jwksSource, issuer, alg string) (jwks.Set, error) {
+	if strings.HasPrefix(alg, "HS") {
+		return jwks.Set{}, nil
 	}
-	if d < 24*time.Hour {
-		h := int(d.Hours())
-		m := int(math.Mod(d.Minutes(), 60))
-		if m > 0 {
-			return fmt.Sprintf("%dh %dm", h, m)
+
+	source := jwksSource
+	if source == "" && issuer != "" {
+		disco, err := oidc.Discover(issuer)
+		if err != nil {
+			return jwks.Set{}, fmt.Errorf("OIDC discovery failed: %w", err)
 		}
-		return fmt.Sprintf("%dh", h)
+		source = disco.JWKSURI
 	}
-	days := int(d.Hours() / 24)
-	if days < 30 {
-		return fmt.Sprintf("%dd", days)
+	if source == "" {
+		return jwks.Set{}, fmt.Errorf("--verify requires --jwks or --issuer (unless alg is HS256/384/512 with --secret)")
 	}
-	if days < 365 {
-		return fmt.Sprintf("%dmo", days/30)
+	set, err := jwks.Load(source)
+	if err != nil {
+		return jwks.Set{}, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+	return set, nil
+}
+
+// batchJWKSCache resolves and caches the JWK Set used across a --batch run.
+// jwksSource/issuer are fixed for the whole run, so the downloaded JWKS is
+// fetched once and reused — but only for lines whose alg actually needs it.
+// HS* lines skip the lookup entirely (checked against --secret instead) and
+// must never populate or consult the cache: a batch mixing HS256 and RS256
+// lines would otherwise see the RS256 line verify against the empty Set
+// left behind by the HS256 one.
+type batchJWKSCache struct {
+	jwksSource, issuer string
+	resolve            func(jwksSource, issuer, alg string) (jwks.Set, error)
+
+	set    jwks.Set
+	loaded bool
+}
+
+func newBatchJWKSCache(jwksSource, issuer string) *batchJWKSCache {
+	return &batchJWKSCache{jwksSource: jwksSource, issuer: issuer, resolve: resolveSet}
+}
+
+// setFor returns the JWK Set to verify alg against, resolving and caching
+// it the first time a non-HS alg is seen.
+func (c *batchJWKSCache) setFor(alg string) (jwks.Set, error) {
+	if strings.HasPrefix(alg, "HS") {
+		return jwks.Set{}, nil
+	}
+	if !c.loaded {
+		s, err := c.resolve(c.jwksSource, c.issuer, alg)
+		if err != nil {
+			return jwks.Set{}, err
+		}
+		c.set, c.loaded = s, true
+	}
+	return c.set, nil
+}
+
+// runVerify checks parts' signature (parts[0]+"."+parts[1] against
+// parts[2]) and standard claims.
+func runVerify(parts []string, tok jut.Token, set jwks.Set, issuer, audience, secret string) (verify.Result, error) {
+	sig, err := verify.DecodeSignature(parts[2])
+	if err != nil {
+		return verify.Result{}, fmt.Errorf("failed to decode signature: %w", err)
 	}
-	return fmt.Sprintf("%dy", days/365)
+	signingInput := parts[0] + "." + parts[1]
+	return verify.Verify(signingInput, sig, tok.Header, tok.Payload, set, verify.Options{
+		Issuer:   issuer,
+		Audience: audience,
+		Secret:   secret,
+	}, clock.Now()), nil
 }
 
 func fatal(format string, args ...interface{}) {
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jack-work/jut/internal/jwks"
+)
+
+// TestBatchJWKSCacheMixedAlgs guards against the bug fixed in
+// 3c28ed5: an HS256 line ahead of an RS256 line left the RS256 line
+// verifying against the empty Set cached for the HS256 one.
+func TestBatchJWKSCacheMixedAlgs(t *testing.T) {
+	calls := 0
+	cache := &batchJWKSCache{
+		jwksSource: "https://example.com/jwks.json",
+		resolve: func(jwksSource, issuer, alg string) (jwks.Set, error) {
+			calls++
+			return jwks.Set{Keys: []jwks.JWK{{Kid: "rsa-1", Alg: alg}}}, nil
+		},
+	}
+
+	set, err := cache.setFor("HS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 0 {
+		t.Fatalf("HS256 should skip the JWKS lookup, got %+v", set)
+	}
+	if calls != 0 {
+		t.Fatalf("expected resolve not called for HS256, got %d calls", calls)
+	}
+
+	set, err = cache.setFor("RS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "rsa-1" {
+		t.Fatalf("expected the resolved JWKS for RS256, got %+v", set)
+	}
+	if calls != 1 {
+		t.Fatalf("expected resolve called once for RS256, got %d calls", calls)
+	}
+
+	set, err = cache.setFor("RS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 {
+		t.Fatalf("expected the cached JWKS to be reused, got %+v", set)
+	}
+	if calls != 1 {
+		t.Fatalf("expected resolve still only called once, got %d calls", calls)
+	}
+}
+
+func TestBatchJWKSCacheResolveError(t *testing.T) {
+	cache := &batchJWKSCache{
+		resolve: func(jwksSource, issuer, alg string) (jwks.Set, error) {
+			return jwks.Set{}, errors.New("boom")
+		},
+	}
+	if _, err := cache.setFor("RS256"); err == nil {
+		t.Fatal("expected the resolve error to propagate")
+	}
+}
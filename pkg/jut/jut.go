@@ -0,0 +1,106 @@
+// Package jut decodes JWTs: the library half of the jut CLI, factored out
+// so other Go tools can decode tokens without shelling out.
+package jut
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// Token is a decoded JWT's header and payload.
+type Token struct {
+	Header  map[string]interface{}
+	Payload map[string]interface{}
+}
+
+// Decode splits raw on "." and decodes its header and payload segments.
+// Unsigned tokens (two segments) are accepted; the signature, if present,
+// is left to the caller.
+func Decode(raw string) (Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Token{}, fmt.Errorf("invalid JWT: expected 2 or 3 dot-separated segments, got %d", len(parts))
+	}
+
+	header, err := DecodeSegment(parts[0])
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to decode header: %w", err)
+	}
+	payload, err := DecodeSegment(parts[1])
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	var hdr, pld map[string]interface{}
+	if err := json.Unmarshal(header, &hdr); err != nil {
+		return Token{}, fmt.Errorf("failed to parse header: %w", err)
+	}
+	if err := json.Unmarshal(payload, &pld); err != nil {
+		return Token{}, fmt.Errorf("failed to parse payload: %w", err)
+	}
+	return Token{Header: hdr, Payload: pld}, nil
+}
+
+// Expired reports whether the token's exp claim, if present, is before now.
+func (t Token) Expired(now time.Time) bool {
+	exp, ok := t.Payload["exp"].(float64)
+	if !ok {
+		return false
+	}
+	return now.After(time.Unix(int64(exp), 0))
+}
+
+// DecodeSegment base64url-decodes a JWT segment and re-marshals it to get
+// consistently formatted JSON.
+func DecodeSegment(seg string) ([]byte, error) {
+	// JWT uses base64url encoding without padding
+	if l := len(seg) % 4; l > 0 {
+		seg += strings.Repeat("=", 4-l)
+	}
+	decoded, err := base64.URLEncoding.DecodeString(seg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-marshal to get consistently formatted JSON
+	var obj map[string]interface{}
+	if err := json.Unmarshal(decoded, &obj); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return json.Marshal(obj)
+}
+
+// GetToken resolves the token to decode: the first CLI arg, or stdin when
+// piped, or the clipboard as a last resort.
+func GetToken(args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.TrimSpace(args[0]), nil
+	}
+
+	stat, _ := os.Stdin.Stat()
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	// No args, no pipe — try clipboard
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("clipboard is empty")
+	}
+	return text, nil
+}